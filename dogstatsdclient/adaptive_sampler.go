@@ -0,0 +1,72 @@
+package dogstatsdclient
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// AdaptiveSampler is a token-bucket keep/drop decider that caps a metric to
+// at most targetRatePerSec reported occurrences per second no matter how
+// often Allow is called.
+//
+// A statsd "rate" is only honored on the underlying statsd.Client's
+// non-aggregated send path. When Options.Aggregation enables client-side
+// aggregation, the aggregated path for Count/Gauge/Set never looks at rate,
+// so a computed rate would be discarded and every call reported at full,
+// un-sampled volume. Allow instead makes the keep/drop decision itself and
+// the caller only forwards the value to the underlying client when Allow
+// returns true, so nothing downstream needs to understand rate for this to
+// work.
+type AdaptiveSampler struct {
+	mu         sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewAdaptiveSampler creates an AdaptiveSampler.
+func NewAdaptiveSampler() *AdaptiveSampler {
+	return &AdaptiveSampler{}
+}
+
+// Allow reports an occurrence of the sampled event and returns whether it
+// should be reported, given targetRatePerSec desired reported events per
+// second. The bucket refills at targetRatePerSec tokens/second, capped at a
+// burst of targetRatePerSec (one second worth of tokens). Once the bucket is
+// empty, the remaining fraction of a token is used as the probability of a
+// coin flip, so the long-run accepted rate still converges on
+// targetRatePerSec instead of hard-cutting to zero between refills.
+func (a *AdaptiveSampler) Allow(targetRatePerSec float64) bool {
+	if targetRatePerSec <= 0 {
+		return true
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	if a.lastRefill.IsZero() {
+		a.tokens = targetRatePerSec
+	} else {
+		elapsed := now.Sub(a.lastRefill).Seconds()
+		a.tokens += elapsed * targetRatePerSec
+		if a.tokens > targetRatePerSec {
+			a.tokens = targetRatePerSec
+		}
+	}
+	a.lastRefill = now
+
+	if a.tokens >= 1 {
+		a.tokens--
+		return true
+	}
+
+	chance := a.tokens
+	if chance <= 0 || rand.Float64() >= chance {
+		return false
+	}
+	// a kept call always costs a whole token, leaving the bucket in debt,
+	// so fractional accepts cannot push the rate above targetRatePerSec.
+	a.tokens--
+	return true
+}
@@ -0,0 +1,31 @@
+package dogstatsdclient
+
+import "testing"
+
+func TestAdaptiveSamplerAllowBurst(t *testing.T) {
+	s := NewAdaptiveSampler()
+
+	// The first burst, up to targetRatePerSec, must always be allowed: the
+	// bucket starts full.
+	const target = 5.0
+	for i := 0; i < int(target); i++ {
+		if !s.Allow(target) {
+			t.Fatalf("call %d: expected allow within initial burst", i)
+		}
+	}
+
+	// The bucket is now empty and refill is negligible immediately after the
+	// burst, so the next call must not be unconditionally allowed.
+	if s.tokens >= 1 {
+		t.Fatalf("expected tokens to be exhausted after burst, got %f", s.tokens)
+	}
+}
+
+func TestAdaptiveSamplerAllowDisabled(t *testing.T) {
+	s := NewAdaptiveSampler()
+	for i := 0; i < 1000; i++ {
+		if !s.Allow(0) {
+			t.Fatalf("call %d: targetRatePerSec<=0 must always allow", i)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package dogstatsdclient
+
+import (
+	"os"
+	"regexp"
+)
+
+// containerIDRegex matches the 64 hex-character container ID found in both
+// cgroup v1 lines (e.g. "12:memory:/docker/<id>") and cgroup v2 lines (e.g.
+// "0::/system.slice/docker-<id>.scope").
+var containerIDRegex = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// detectContainerID resolves the local container ID by parsing
+// /proc/self/cgroup. It returns "" when not running in a container, or on
+// platforms (such as Windows) where the file does not exist.
+func detectContainerID() string {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return ""
+	}
+	return containerIDRegex.FindString(string(data))
+}
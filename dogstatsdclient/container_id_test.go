@@ -0,0 +1,38 @@
+package dogstatsdclient
+
+import "testing"
+
+func TestContainerIDRegex(t *testing.T) {
+	const id = "2d8d824339fd678cf6ba3ca667ff2bc6a4ce84175e8dcc50e1d8c2b7fec92c2d"
+
+	table := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "cgroup v1 docker",
+			line: "12:memory:/docker/" + id,
+			want: id,
+		},
+		{
+			name: "cgroup v2",
+			line: "0::/system.slice/docker-" + id + ".scope",
+			want: id,
+		},
+		{
+			name: "no container",
+			line: "0::/init.scope",
+			want: "",
+		},
+	}
+
+	for _, row := range table {
+		t.Run(row.name, func(t *testing.T) {
+			got := containerIDRegex.FindString(row.line)
+			if got != row.want {
+				t.Errorf("got %q, want %q", got, row.want)
+			}
+		})
+	}
+}
@@ -8,9 +8,12 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/prometheus/client_golang/prometheus"
+	otelmetric "go.opentelemetry.io/otel/metric"
 )
 
 // Options define options for datadog client.
@@ -44,8 +47,112 @@ type Options struct {
 	// The internal client is renewed every TTL period in order to withstand DNS changes.
 	// If unspecified, defaults to 1 minute.
 	TTL time.Duration
+
+	// SocketPath is the filesystem path to the Dogstatsd socket (for example
+	// "/var/run/datadog/dsd.socket") when TransportProtocol is "uds", or the pipe
+	// name when it is "pipe"; Host/Port are then ignored. SocketPath alone does
+	// not select a transport: with any other TransportProtocol it is ignored.
+	SocketPath string
+
+	// TransportProtocol selects the transport used to reach the Agent: "udp" (default),
+	// "uds" (Unix Domain Socket datagram, requires SocketPath) or "pipe" (Windows named
+	// pipe, requires SocketPath as the pipe name).
+	TransportProtocol string
+
+	// EntityID defaults to env var DD_ENTITY_ID. When set, it is added as tag
+	// "dd.internal.entity_id" so the Agent can attribute metrics to the pod that
+	// sent them.
+	EntityID string
+
+	// OriginDetection enables statsd.WithOriginDetection, so the Agent can
+	// attribute metrics to the sending container via Unix Domain Socket
+	// credentials or the cgroup controller inode.
+	OriginDetection bool
+
+	// DisableContainerID prevents NewUnsafe from resolving the local container
+	// ID from /proc/self/cgroup and passing it via statsd.WithContainerID.
+	DisableContainerID bool
+
+	// DefaultSampleRate is applied whenever a caller passes rate<=0 to Count,
+	// Gauge, TimeInMilliseconds and the other metric methods. If unspecified,
+	// defaults to 1 (always sample).
+	DefaultSampleRate float64
+
+	// OnError, when set, is invoked whenever the underlying statsd.Client
+	// metric method returns an error (dropped metric) and whenever the internal
+	// statsd.Client reports an async transport error via statsd.WithErrorHandler.
+	// metric is empty for async transport errors, since they are not tied to a
+	// single call. Both cases also increment Stats().Drops, whether or not
+	// OnError is set. A call that fails because the internal client could not
+	// be created or renewed returns that error without invoking OnError or
+	// incrementing Drops; it is counted in Stats().RenewalErrors instead.
+	OnError func(err error, metric string)
+
+	// Aggregation configures client-side aggregation/buffering. If unspecified
+	// (both Enabled and Extended false), statsd.WithoutClientSideAggregation is
+	// used, so the client sends one datagram per call, as before. datadog-go/v5
+	// otherwise enables client-side aggregation by default on its own.
+	Aggregation AggregationOptions
+
+	// Backend selects the metrics sink created by NewSink: BackendDogstatsd
+	// (default), BackendPrometheus, BackendOTLP or BackendMock.
+	Backend string
+
+	// PrometheusRegisterer is the registry metrics are registered on when
+	// Backend is BackendPrometheus. Defaults to prometheus.DefaultRegisterer.
+	PrometheusRegisterer prometheus.Registerer
+
+	// OTelMeter is the OpenTelemetry meter used to create instruments when
+	// Backend is BackendOTLP. It must already be wired by the caller to
+	// whatever MeterProvider/exporter delivers the OTLP metrics. Required
+	// when Backend is BackendOTLP.
+	OTelMeter otelmetric.Meter
+}
+
+// AggregationOptions configures the buffered/aggregated Dogstatsd client mode,
+// wiring through to the matching statsd.With* options. Periodic flushing of
+// aggregated samples is handled entirely by the underlying statsd.Client's own
+// background sender on Interval, not by this wrapper: Client itself only
+// forces a flush around TTL-driven renewal and Close, so buffered samples are
+// not lost when the internal client is rotated or shut down.
+type AggregationOptions struct {
+	// Enabled turns on client-side aggregation via statsd.WithClientSideAggregation.
+	Enabled bool
+
+	// Extended turns on extended client-side aggregation (histograms, distributions,
+	// timings) via statsd.WithExtendedClientSideAggregation. Extended implies Enabled.
+	Extended bool
+
+	// Interval sets the aggregation flush interval via statsd.WithAggregationInterval.
+	// If unspecified, the datadog-go default is used. The flush itself is
+	// performed by statsd.Client's background sender, not by this wrapper.
+	Interval time.Duration
+
+	// MaxBytesPerPayload sets the maximum payload size via statsd.WithMaxBytesPerPayload.
+	// If unspecified, the datadog-go default is used.
+	MaxBytesPerPayload int
+
+	// BufferPoolSize sets the number of buffers kept in the pool via
+	// statsd.WithBufferPoolSize. If unspecified, the datadog-go default is used.
+	BufferPoolSize int
+
+	// ChannelMode sends samples to the sender goroutine over a channel instead of
+	// using a mutex, via statsd.WithChannelMode.
+	ChannelMode bool
+
+	// ChannelModeBufferSize sets the channel buffer size via
+	// statsd.WithChannelModeBufferSize. Only takes effect when ChannelMode is true.
+	// If unspecified, the datadog-go default is used.
+	ChannelModeBufferSize int
 }
 
+// Transport protocol names accepted in Options.TransportProtocol.
+const (
+	TransportUDP  = "udp"
+	TransportUDS  = "uds"
+	TransportPipe = "pipe"
+)
+
 // Client holds Dogstatsd client.
 // Client implements the interface DogstatsdClient.
 type Client struct {
@@ -53,6 +160,89 @@ type Client struct {
 	client         *statsd.Client
 	clientCreation time.Time
 	lock           sync.Mutex
+	samplers       map[string]*AdaptiveSampler
+
+	drops         uint64
+	renewals      uint64
+	renewalErrors uint64
+}
+
+// Stats is a snapshot of Client self-telemetry, returned by Client.Stats.
+type Stats struct {
+	// Drops counts calls whose underlying statsd.Client method returned an
+	// error, plus async transport errors reported by the internal
+	// statsd.Client's background sender (for example, the Agent being
+	// unreachable), which never surface as a synchronous method return.
+	Drops uint64
+
+	// Renewals counts successful internal client creations: the first one,
+	// made by New, and each renewal triggered by TTL expiration.
+	Renewals uint64
+
+	// RenewalErrors counts failed internal client renewal attempts.
+	RenewalErrors uint64
+}
+
+// Stats returns a snapshot of the client's self-telemetry counters.
+func (c *Client) Stats() Stats {
+	return Stats{
+		Drops:         atomic.LoadUint64(&c.drops),
+		Renewals:      atomic.LoadUint64(&c.renewals),
+		RenewalErrors: atomic.LoadUint64(&c.renewalErrors),
+	}
+}
+
+// trackError records a dropped metric and invokes options.OnError, if set.
+// It always returns err unchanged, so callers can do "return c.trackError(err, name)".
+func (c *Client) trackError(err error, metric string) error {
+	if err != nil {
+		atomic.AddUint64(&c.drops, 1)
+		if c.options.OnError != nil {
+			c.options.OnError(err, metric)
+		}
+	}
+	return err
+}
+
+// trackedOptions returns a copy of c.options whose OnError also increments
+// c.drops, so Stats().Drops reflects async transport errors (agent down,
+// socket missing, ...) reported by the internal statsd.Client's background
+// sender, not just errors returned synchronously from a metric method call.
+func (c *Client) trackedOptions() Options {
+	original := c.options.OnError
+	options := c.options
+	options.OnError = func(err error, metric string) {
+		atomic.AddUint64(&c.drops, 1)
+		if original != nil {
+			original(err, metric)
+		}
+	}
+	return options
+}
+
+// selfTelemetryNames are the metric names used by emitSelfTelemetry.
+const (
+	selfTelemetryDrops         = "dogstatsdclient.drops"
+	selfTelemetryRenewals      = "dogstatsdclient.renewals"
+	selfTelemetryRenewalErrors = "dogstatsdclient.renewal_errors"
+)
+
+// emitSelfTelemetry reports the current Stats snapshot as gauges, sent
+// directly through client so it does not recurse into renewIfExpired/trackError.
+// Gauges are used instead of counters because Stats holds cumulative totals,
+// not per-period deltas.
+func (c *Client) emitSelfTelemetry(client *statsd.Client) {
+	const me = "dogstatsdclient.emitSelfTelemetry"
+	stats := c.Stats()
+	if err := client.Gauge(selfTelemetryDrops, float64(stats.Drops), nil, 1); err != nil && c.options.Debug {
+		slog.Info(me, "metric", selfTelemetryDrops, "error", err)
+	}
+	if err := client.Gauge(selfTelemetryRenewals, float64(stats.Renewals), nil, 1); err != nil && c.options.Debug {
+		slog.Info(me, "metric", selfTelemetryRenewals, "error", err)
+	}
+	if err := client.Gauge(selfTelemetryRenewalErrors, float64(stats.RenewalErrors), nil, 1); err != nil && c.options.Debug {
+		slog.Info(me, "metric", selfTelemetryRenewalErrors, "error", err)
+	}
 }
 
 const defaultTTL = time.Minute
@@ -93,23 +283,94 @@ func (c *Client) renewIfExpired() error {
 		)
 	}
 	// client has expired
-	client, err := NewUnsafe(c.options)
+	client, err := NewUnsafe(c.trackedOptions())
 	if err != nil {
+		atomic.AddUint64(&c.renewalErrors, 1)
 		return err
 	}
 	if c.client != nil {
+		// drain buffered/aggregated samples before closing, so rotation does not
+		// drop metrics that are still sitting in the old client's buffer.
+		if errFlush := c.client.Flush(); errFlush != nil && c.options.Debug {
+			slog.Info(me, "flush-before-close-error", errFlush)
+		}
 		c.client.Close()
 	}
 	c.client = client
 	c.clientCreation = time.Now()
+	atomic.AddUint64(&c.renewals, 1)
+	c.emitSelfTelemetry(client)
 	return nil
 }
 
+// Flush forces a flush of all the queued dogstatsd payloads.
+func (c *Client) Flush() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.renewIfExpired(); err != nil {
+		return err
+	}
+	return c.client.Flush()
+}
+
 // Close the client connection.
 func (c *Client) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.client == nil {
+		return nil
+	}
+	if errFlush := c.client.Flush(); errFlush != nil && c.options.Debug {
+		slog.Info("dogstatsdclient.Close", "flush-before-close-error", errFlush)
+	}
 	return c.client.Close()
 }
 
+// effectiveRate applies options.DefaultSampleRate whenever the caller passes
+// rate<=0, so hot paths do not need to precompute a sample rate.
+func (c *Client) effectiveRate(rate float64) float64 {
+	if rate > 0 {
+		return rate
+	}
+	if c.options.DefaultSampleRate > 0 {
+		return c.options.DefaultSampleRate
+	}
+	return 1
+}
+
+// CountSampled caps how often a high-frequency event is reported, without
+// requiring the caller to precompute a sample rate. targetRatePerSec is the
+// desired upper bound on reported events per second for this metric name; an
+// internal AdaptiveSampler decides whether this particular call is kept, and
+// only kept calls reach Count, carrying their original, unscaled value.
+//
+// This does not pass a fractional rate down to Count: when Options.Aggregation
+// enables client-side aggregation, the value reported upstream is never
+// rescaled by rate, so a rate-based call here would silently report every
+// occurrence at full volume regardless of targetRatePerSec.
+func (c *Client) CountSampled(name string, value int64, tags []string, targetRatePerSec float64) error {
+	if !c.sampler(name).Allow(targetRatePerSec) {
+		return nil
+	}
+	return c.Count(name, value, tags, 1)
+}
+
+// sampler returns the AdaptiveSampler tracking the given metric name,
+// creating one on first use.
+func (c *Client) sampler(name string) *AdaptiveSampler {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.samplers == nil {
+		c.samplers = map[string]*AdaptiveSampler{}
+	}
+	s, found := c.samplers[name]
+	if !found {
+		s = NewAdaptiveSampler()
+		c.samplers[name] = s
+	}
+	return s
+}
+
 // Count tracks how many times something happened per second.
 func (c *Client) Count(name string, value int64, tags []string, rate float64) error {
 	const me = "dogstatsdclient.Count"
@@ -118,8 +379,10 @@ func (c *Client) Count(name string, value int64, tags []string, rate float64) er
 	if err := c.renewIfExpired(); err != nil {
 		return err
 	}
+	rate = c.effectiveRate(rate)
 	c.debug(me, name, value, tags, rate)
-	return c.client.Count(name, value, tags, rate)
+	err := c.client.Count(name, value, tags, rate)
+	return c.trackError(err, name)
 }
 
 // Gauge measures the value of a metric at a particular time.
@@ -130,8 +393,10 @@ func (c *Client) Gauge(name string, value float64, tags []string, rate float64)
 	if err := c.renewIfExpired(); err != nil {
 		return err
 	}
+	rate = c.effectiveRate(rate)
 	c.debug(me, name, value, tags, rate)
-	return c.client.Gauge(name, value, tags, rate)
+	err := c.client.Gauge(name, value, tags, rate)
+	return c.trackError(err, name)
 }
 
 // TimeInMilliseconds sends timing information in milliseconds.
@@ -142,8 +407,120 @@ func (c *Client) TimeInMilliseconds(name string, value float64, tags []string, r
 	if err := c.renewIfExpired(); err != nil {
 		return err
 	}
+	rate = c.effectiveRate(rate)
 	c.debug(me, name, value, tags, rate)
-	return c.client.TimeInMilliseconds(name, value, tags, rate)
+	err := c.client.TimeInMilliseconds(name, value, tags, rate)
+	return c.trackError(err, name)
+}
+
+// Histogram tracks the statistical distribution of a set of values on each host.
+func (c *Client) Histogram(name string, value float64, tags []string, rate float64) error {
+	const me = "dogstatsdclient.Histogram"
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.renewIfExpired(); err != nil {
+		return err
+	}
+	rate = c.effectiveRate(rate)
+	c.debug(me, name, value, tags, rate)
+	err := c.client.Histogram(name, value, tags, rate)
+	return c.trackError(err, name)
+}
+
+// Distribution tracks the statistical distribution of a set of values across your infrastructure.
+func (c *Client) Distribution(name string, value float64, tags []string, rate float64) error {
+	const me = "dogstatsdclient.Distribution"
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.renewIfExpired(); err != nil {
+		return err
+	}
+	rate = c.effectiveRate(rate)
+	c.debug(me, name, value, tags, rate)
+	err := c.client.Distribution(name, value, tags, rate)
+	return c.trackError(err, name)
+}
+
+// Set counts the number of unique elements in a group.
+func (c *Client) Set(name string, value string, tags []string, rate float64) error {
+	const me = "dogstatsdclient.Set"
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.renewIfExpired(); err != nil {
+		return err
+	}
+	rate = c.effectiveRate(rate)
+	c.debug(me, name, value, tags, rate)
+	err := c.client.Set(name, value, tags, rate)
+	return c.trackError(err, name)
+}
+
+// Incr is just Count of 1.
+func (c *Client) Incr(name string, tags []string, rate float64) error {
+	const me = "dogstatsdclient.Incr"
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.renewIfExpired(); err != nil {
+		return err
+	}
+	rate = c.effectiveRate(rate)
+	c.debug(me, name, 1, tags, rate)
+	err := c.client.Incr(name, tags, rate)
+	return c.trackError(err, name)
+}
+
+// Decr is just Count of -1.
+func (c *Client) Decr(name string, tags []string, rate float64) error {
+	const me = "dogstatsdclient.Decr"
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.renewIfExpired(); err != nil {
+		return err
+	}
+	rate = c.effectiveRate(rate)
+	c.debug(me, name, -1, tags, rate)
+	err := c.client.Decr(name, tags, rate)
+	return c.trackError(err, name)
+}
+
+// Timing sends timing information, it is an alias for TimeInMilliseconds.
+func (c *Client) Timing(name string, value time.Duration, tags []string, rate float64) error {
+	const me = "dogstatsdclient.Timing"
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.renewIfExpired(); err != nil {
+		return err
+	}
+	rate = c.effectiveRate(rate)
+	c.debug(me, name, value, tags, rate)
+	err := c.client.Timing(name, value, tags, rate)
+	return c.trackError(err, name)
+}
+
+// ServiceCheck sends the provided ServiceCheck.
+func (c *Client) ServiceCheck(sc *statsd.ServiceCheck) error {
+	const me = "dogstatsdclient.ServiceCheck"
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.renewIfExpired(); err != nil {
+		return err
+	}
+	c.debug(me, sc.Name, sc.Status, sc.Tags, 1)
+	err := c.client.ServiceCheck(sc)
+	return c.trackError(err, sc.Name)
+}
+
+// Event sends the provided Event.
+func (c *Client) Event(e *statsd.Event) error {
+	const me = "dogstatsdclient.Event"
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.renewIfExpired(); err != nil {
+		return err
+	}
+	c.debug(me, e.Title, e.Text, e.Tags, 1)
+	err := c.client.Event(e)
+	return c.trackError(err, e.Title)
 }
 
 func (c *Client) debug(caller string, name string, value any, tags []string, rate float64) {
@@ -197,29 +574,119 @@ func NewUnsafe(options Options) (*statsd.Client, error) {
 	// add service to tags
 	options.Tags = append(options.Tags, fmt.Sprintf("service:%s", options.Service))
 
+	if options.EntityID == "" {
+		options.EntityID = envString("DD_ENTITY_ID", "")
+	}
+	if options.EntityID != "" {
+		// classic entity-ID tagging, used when origin detection over UDS is unavailable.
+		options.Tags = append(options.Tags, fmt.Sprintf("dd.internal.entity_id:%s", options.EntityID))
+	}
+
+	if namespace := envString("POD_NAMESPACE", ""); namespace != "" {
+		options.Tags = append(options.Tags, fmt.Sprintf("kube_namespace:%s", namespace))
+	}
+	if deployment := envString("KUBE_DEPLOYMENT", ""); deployment != "" {
+		options.Tags = append(options.Tags, fmt.Sprintf("kube_deployment:%s", deployment))
+	}
+
 	// compact tags
 	slices.Sort(options.Tags)
 	options.Tags = slices.Compact(options.Tags)
 
-	host := fmt.Sprintf("%s:%s", options.Host, options.Port)
+	addr := statsdAddress(options)
 
 	if options.Debug {
 		slog.Info(
 			me,
-			"host", host,
+			"addr", addr,
+			"transportProtocol", options.TransportProtocol,
 			"namespace", options.Namespace,
 			"service", options.Service,
 			"tags", options.Tags,
 		)
 	}
 
-	c, err := statsd.New(host,
+	statsdOptions := []statsd.Option{
 		statsd.WithNamespace(options.Namespace),
-		statsd.WithTags(options.Tags))
+		statsd.WithTags(options.Tags),
+	}
+	statsdOptions = append(statsdOptions, aggregationOptions(options.Aggregation)...)
+
+	if options.OriginDetection {
+		statsdOptions = append(statsdOptions, statsd.WithOriginDetection())
+	}
+
+	if !options.DisableContainerID {
+		if containerID := detectContainerID(); containerID != "" {
+			if options.Debug {
+				slog.Info(me, "containerID", containerID)
+			}
+			statsdOptions = append(statsdOptions, statsd.WithContainerID(containerID))
+		}
+	}
+
+	if options.OnError != nil {
+		onError := options.OnError
+		statsdOptions = append(statsdOptions, statsd.WithErrorHandler(func(err error) {
+			onError(err, "")
+		}))
+	}
+
+	c, err := statsd.New(addr, statsdOptions...)
 
 	return c, err
 }
 
+// aggregationOptions translates AggregationOptions into statsd.Option values.
+func aggregationOptions(agg AggregationOptions) []statsd.Option {
+	var options []statsd.Option
+
+	if agg.Extended {
+		options = append(options, statsd.WithExtendedClientSideAggregation())
+	} else if agg.Enabled {
+		options = append(options, statsd.WithClientSideAggregation())
+	} else {
+		// datadog-go/v5 enables client-side aggregation for Count/Gauge/Set by
+		// default, which would silently change per-call behavior for callers
+		// who never opted into AggregationOptions. Opt back out explicitly.
+		options = append(options, statsd.WithoutClientSideAggregation())
+	}
+
+	if agg.Interval > 0 {
+		options = append(options, statsd.WithAggregationInterval(agg.Interval))
+	}
+
+	if agg.MaxBytesPerPayload > 0 {
+		options = append(options, statsd.WithMaxBytesPerPayload(agg.MaxBytesPerPayload))
+	}
+
+	if agg.BufferPoolSize > 0 {
+		options = append(options, statsd.WithBufferPoolSize(agg.BufferPoolSize))
+	}
+
+	if agg.ChannelMode {
+		options = append(options, statsd.WithChannelMode())
+		if agg.ChannelModeBufferSize > 0 {
+			options = append(options, statsd.WithChannelModeBufferSize(agg.ChannelModeBufferSize))
+		}
+	}
+
+	return options
+}
+
+// statsdAddress builds the address passed to statsd.New, picking the transport
+// prefix expected by datadog-go according to options.TransportProtocol.
+func statsdAddress(options Options) string {
+	switch options.TransportProtocol {
+	case TransportUDS:
+		return statsd.UnixAddressPrefix + options.SocketPath
+	case TransportPipe:
+		return statsd.WindowsPipeAddressPrefix + options.SocketPath
+	default:
+		return fmt.Sprintf("%s:%s", options.Host, options.Port)
+	}
+}
+
 // envString extracts string from env var.
 // It returns the provided defaultValue if the env var is empty.
 // The string returned is also recorded in logs.
@@ -248,6 +715,33 @@ type DogstatsdClient interface {
 	// TimeInMilliseconds sends timing information in milliseconds.
 	TimeInMilliseconds(name string, value float64, tags []string, rate float64) error
 
+	// Histogram tracks the statistical distribution of a set of values on each host.
+	Histogram(name string, value float64, tags []string, rate float64) error
+
+	// Distribution tracks the statistical distribution of a set of values across your infrastructure.
+	Distribution(name string, value float64, tags []string, rate float64) error
+
+	// Set counts the number of unique elements in a group.
+	Set(name string, value string, tags []string, rate float64) error
+
+	// Incr is just Count of 1.
+	Incr(name string, tags []string, rate float64) error
+
+	// Decr is just Count of -1.
+	Decr(name string, tags []string, rate float64) error
+
+	// Timing sends timing information, it is an alias for TimeInMilliseconds.
+	Timing(name string, value time.Duration, tags []string, rate float64) error
+
+	// ServiceCheck sends the provided ServiceCheck.
+	ServiceCheck(sc *statsd.ServiceCheck) error
+
+	// Event sends the provided Event.
+	Event(e *statsd.Event) error
+
+	// Flush forces a flush of all the queued dogstatsd payloads.
+	Flush() error
+
 	// Close the client connection.
 	Close() error
 }
@@ -0,0 +1,109 @@
+package dogstatsdclient
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+)
+
+func TestAggregationOptionsDefaultDisablesAggregation(t *testing.T) {
+	// Unspecified AggregationOptions must still produce an Option, so the
+	// client explicitly opts out of datadog-go/v5's own aggregation default
+	// instead of silently inheriting it.
+	options := aggregationOptions(AggregationOptions{})
+	if len(options) != 1 {
+		t.Fatalf("expected exactly one statsd.Option for default AggregationOptions, got %d", len(options))
+	}
+}
+
+func TestAggregationOptionsEnabled(t *testing.T) {
+	options := aggregationOptions(AggregationOptions{Enabled: true})
+	if len(options) != 1 {
+		t.Fatalf("expected exactly one statsd.Option when Enabled, got %d", len(options))
+	}
+}
+
+func TestAggregationOptionsExtended(t *testing.T) {
+	options := aggregationOptions(AggregationOptions{Extended: true})
+	if len(options) != 1 {
+		t.Fatalf("expected exactly one statsd.Option when Extended, got %d", len(options))
+	}
+}
+
+func TestTrackedOptionsIncrementsDropsOnAsyncError(t *testing.T) {
+	var gotErr error
+	var gotMetric string
+	c := &Client{
+		options: Options{
+			OnError: func(err error, metric string) {
+				gotErr = err
+				gotMetric = metric
+			},
+		},
+	}
+
+	sentinel := errors.New("agent unreachable")
+	c.trackedOptions().OnError(sentinel, "")
+
+	if stats := c.Stats(); stats.Drops != 1 {
+		t.Fatalf("expected Drops to be 1, got %d", stats.Drops)
+	}
+	if gotErr != sentinel {
+		t.Fatalf("expected original OnError to still receive the error, got %v", gotErr)
+	}
+	if gotMetric != "" {
+		t.Fatalf("expected empty metric for an async error, got %q", gotMetric)
+	}
+}
+
+func TestTrackedOptionsIncrementsDropsWithoutOnError(t *testing.T) {
+	c := &Client{}
+	c.trackedOptions().OnError(errors.New("boom"), "")
+	if stats := c.Stats(); stats.Drops != 1 {
+		t.Fatalf("expected Drops to be 1 even without a caller OnError, got %d", stats.Drops)
+	}
+}
+
+func TestStatsdAddress(t *testing.T) {
+	table := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{
+			name: "udp default",
+			opts: Options{Host: "localhost", Port: "8125"},
+			want: "localhost:8125",
+		},
+		{
+			name: "udp explicit",
+			opts: Options{Host: "localhost", Port: "8125", TransportProtocol: TransportUDP},
+			want: "localhost:8125",
+		},
+		{
+			name: "uds",
+			opts: Options{TransportProtocol: TransportUDS, SocketPath: "/var/run/datadog/dsd.socket"},
+			want: statsd.UnixAddressPrefix + "/var/run/datadog/dsd.socket",
+		},
+		{
+			name: "pipe",
+			opts: Options{TransportProtocol: TransportPipe, SocketPath: "datadog-dogstatsd"},
+			want: statsd.WindowsPipeAddressPrefix + "datadog-dogstatsd",
+		},
+		{
+			name: "unknown transport falls back to host:port",
+			opts: Options{Host: "localhost", Port: "8125", TransportProtocol: "bogus"},
+			want: "localhost:8125",
+		},
+	}
+
+	for _, row := range table {
+		t.Run(row.name, func(t *testing.T) {
+			got := statsdAddress(row.opts)
+			if got != row.want {
+				t.Errorf("got %q, want %q", got, row.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package dogstatsdclient
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+)
+
+// MockSink is a Sink that only logs calls, sending nothing anywhere.
+// MockSink implements the interface DogstatsdClient.
+type MockSink struct {
+	options Options
+}
+
+// NewMockSink creates a MockSink.
+func NewMockSink(options Options) *MockSink {
+	return &MockSink{options: options}
+}
+
+func (m *MockSink) debug(caller string, name string, value any, tags []string, rate float64) {
+	slog.Info(caller,
+		"name", name,
+		"value", value,
+		"tags", tags,
+		"rate", rate,
+	)
+}
+
+// Count tracks how many times something happened per second.
+func (m *MockSink) Count(name string, value int64, tags []string, rate float64) error {
+	m.debug("dogstatsdclient.MockSink.Count", name, value, tags, rate)
+	return nil
+}
+
+// Gauge measures the value of a metric at a particular time.
+func (m *MockSink) Gauge(name string, value float64, tags []string, rate float64) error {
+	m.debug("dogstatsdclient.MockSink.Gauge", name, value, tags, rate)
+	return nil
+}
+
+// TimeInMilliseconds sends timing information in milliseconds.
+func (m *MockSink) TimeInMilliseconds(name string, value float64, tags []string, rate float64) error {
+	m.debug("dogstatsdclient.MockSink.TimeInMilliseconds", name, value, tags, rate)
+	return nil
+}
+
+// Histogram tracks the statistical distribution of a set of values on each host.
+func (m *MockSink) Histogram(name string, value float64, tags []string, rate float64) error {
+	m.debug("dogstatsdclient.MockSink.Histogram", name, value, tags, rate)
+	return nil
+}
+
+// Distribution tracks the statistical distribution of a set of values across your infrastructure.
+func (m *MockSink) Distribution(name string, value float64, tags []string, rate float64) error {
+	m.debug("dogstatsdclient.MockSink.Distribution", name, value, tags, rate)
+	return nil
+}
+
+// Set counts the number of unique elements in a group.
+func (m *MockSink) Set(name string, value string, tags []string, rate float64) error {
+	m.debug("dogstatsdclient.MockSink.Set", name, value, tags, rate)
+	return nil
+}
+
+// Incr is just Count of 1.
+func (m *MockSink) Incr(name string, tags []string, rate float64) error {
+	m.debug("dogstatsdclient.MockSink.Incr", name, 1, tags, rate)
+	return nil
+}
+
+// Decr is just Count of -1.
+func (m *MockSink) Decr(name string, tags []string, rate float64) error {
+	m.debug("dogstatsdclient.MockSink.Decr", name, -1, tags, rate)
+	return nil
+}
+
+// Timing sends timing information, it is an alias for TimeInMilliseconds.
+func (m *MockSink) Timing(name string, value time.Duration, tags []string, rate float64) error {
+	m.debug("dogstatsdclient.MockSink.Timing", name, value, tags, rate)
+	return nil
+}
+
+// ServiceCheck sends the provided ServiceCheck.
+func (m *MockSink) ServiceCheck(sc *statsd.ServiceCheck) error {
+	m.debug("dogstatsdclient.MockSink.ServiceCheck", sc.Name, sc.Status, sc.Tags, 1)
+	return nil
+}
+
+// Event sends the provided Event.
+func (m *MockSink) Event(e *statsd.Event) error {
+	m.debug("dogstatsdclient.MockSink.Event", e.Title, e.Text, e.Tags, 1)
+	return nil
+}
+
+// Flush forces a flush of all the queued dogstatsd payloads.
+func (m *MockSink) Flush() error {
+	return nil
+}
+
+// Close the client connection.
+func (m *MockSink) Close() error {
+	return nil
+}
@@ -0,0 +1,197 @@
+package dogstatsdclient
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+// OTLPSink is a Sink that translates Dogstatsd-shaped calls into
+// OpenTelemetry metric instruments: Count becomes a Counter, Gauge becomes a
+// Gauge, and TimeInMilliseconds/Histogram/Distribution become a Histogram.
+// OTLPSink implements the interface DogstatsdClient.
+type OTLPSink struct {
+	options    Options
+	meter      otelmetric.Meter
+	lock       sync.Mutex
+	counters   map[string]otelmetric.Float64Counter
+	gauges     map[string]otelmetric.Float64Gauge
+	histograms map[string]otelmetric.Float64Histogram
+}
+
+// NewOTLPSink creates an OpenTelemetry-backed Sink from options.OTelMeter.
+func NewOTLPSink(options Options) (*OTLPSink, error) {
+	const me = "dogstatsdclient.NewOTLPSink"
+	if options.OTelMeter == nil {
+		return nil, errors.New(me + ": Options.OTelMeter is required for the otlp backend")
+	}
+	return &OTLPSink{
+		options:    options,
+		meter:      options.OTelMeter,
+		counters:   map[string]otelmetric.Float64Counter{},
+		gauges:     map[string]otelmetric.Float64Gauge{},
+		histograms: map[string]otelmetric.Float64Histogram{},
+	}, nil
+}
+
+// tagAttributes converts Dogstatsd "key:value" tags into OTel attributes.
+func tagAttributes(tags []string) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(tags))
+	for _, tag := range tags {
+		key, value, found := strings.Cut(tag, ":")
+		if !found {
+			attrs = append(attrs, attribute.Bool(key, true))
+			continue
+		}
+		attrs = append(attrs, attribute.String(key, value))
+	}
+	return attrs
+}
+
+func (o *OTLPSink) counter(name string) (otelmetric.Float64Counter, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	c, found := o.counters[name]
+	if found {
+		return c, nil
+	}
+	c, err := o.meter.Float64Counter(name)
+	if err != nil {
+		return nil, err
+	}
+	o.counters[name] = c
+	return c, nil
+}
+
+func (o *OTLPSink) gauge(name string) (otelmetric.Float64Gauge, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	g, found := o.gauges[name]
+	if found {
+		return g, nil
+	}
+	g, err := o.meter.Float64Gauge(name)
+	if err != nil {
+		return nil, err
+	}
+	o.gauges[name] = g
+	return g, nil
+}
+
+func (o *OTLPSink) histogram(name string) (otelmetric.Float64Histogram, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	h, found := o.histograms[name]
+	if found {
+		return h, nil
+	}
+	h, err := o.meter.Float64Histogram(name)
+	if err != nil {
+		return nil, err
+	}
+	o.histograms[name] = h
+	return h, nil
+}
+
+// Count tracks how many times something happened per second.
+func (o *OTLPSink) Count(name string, value int64, tags []string, _ float64) error {
+	c, err := o.counter(name)
+	if err != nil {
+		return err
+	}
+	c.Add(context.Background(), float64(value), otelmetric.WithAttributes(tagAttributes(tags)...))
+	return nil
+}
+
+// Gauge measures the value of a metric at a particular time.
+func (o *OTLPSink) Gauge(name string, value float64, tags []string, _ float64) error {
+	g, err := o.gauge(name)
+	if err != nil {
+		return err
+	}
+	g.Record(context.Background(), value, otelmetric.WithAttributes(tagAttributes(tags)...))
+	return nil
+}
+
+// TimeInMilliseconds sends timing information in milliseconds.
+func (o *OTLPSink) TimeInMilliseconds(name string, value float64, tags []string, _ float64) error {
+	h, err := o.histogram(name)
+	if err != nil {
+		return err
+	}
+	h.Record(context.Background(), value, otelmetric.WithAttributes(tagAttributes(tags)...))
+	return nil
+}
+
+// Histogram tracks the statistical distribution of a set of values on each host.
+func (o *OTLPSink) Histogram(name string, value float64, tags []string, rate float64) error {
+	return o.TimeInMilliseconds(name, value, tags, rate)
+}
+
+// Distribution tracks the statistical distribution of a set of values across your infrastructure.
+func (o *OTLPSink) Distribution(name string, value float64, tags []string, rate float64) error {
+	return o.TimeInMilliseconds(name, value, tags, rate)
+}
+
+// Set counts the number of unique elements in a group.
+//
+// OTel has no native set-cardinality instrument, so the observed value is
+// recorded as an attribute on a counter: true unique-element tracking is
+// left to whatever backend the collector exports to.
+func (o *OTLPSink) Set(name string, value string, tags []string, _ float64) error {
+	c, err := o.counter(name)
+	if err != nil {
+		return err
+	}
+	attrs := append(tagAttributes(tags), attribute.String("value", value))
+	c.Add(context.Background(), 1, otelmetric.WithAttributes(attrs...))
+	return nil
+}
+
+// Incr is just Count of 1.
+func (o *OTLPSink) Incr(name string, tags []string, rate float64) error {
+	return o.Count(name, 1, tags, rate)
+}
+
+// Decr is just Count of -1.
+func (o *OTLPSink) Decr(name string, tags []string, rate float64) error {
+	return o.Count(name, -1, tags, rate)
+}
+
+// Timing sends timing information, it is an alias for TimeInMilliseconds.
+func (o *OTLPSink) Timing(name string, value time.Duration, tags []string, rate float64) error {
+	return o.TimeInMilliseconds(name, float64(value)/float64(time.Millisecond), tags, rate)
+}
+
+// ServiceCheck has no OTel metrics equivalent, it is logged and dropped.
+func (o *OTLPSink) ServiceCheck(sc *statsd.ServiceCheck) error {
+	if o.options.Debug {
+		slog.Info("dogstatsdclient.OTLPSink.ServiceCheck", "name", sc.Name, "status", sc.Status)
+	}
+	return nil
+}
+
+// Event has no OTel metrics equivalent, it is logged and dropped.
+func (o *OTLPSink) Event(e *statsd.Event) error {
+	if o.options.Debug {
+		slog.Info("dogstatsdclient.OTLPSink.Event", "title", e.Title)
+	}
+	return nil
+}
+
+// Flush is a no-op: the caller owns the MeterProvider/exporter lifecycle.
+func (o *OTLPSink) Flush() error {
+	return nil
+}
+
+// Close is a no-op: the caller owns the MeterProvider/exporter lifecycle.
+func (o *OTLPSink) Close() error {
+	return nil
+}
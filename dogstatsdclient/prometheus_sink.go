@@ -0,0 +1,195 @@
+package dogstatsdclient
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusSink is a Sink that registers counters, gauges and histograms on
+// a prometheus.Registerer instead of sending Dogstatsd datagrams.
+// PrometheusSink implements the interface DogstatsdClient.
+type PrometheusSink struct {
+	options    Options
+	registerer prometheus.Registerer
+	lock       sync.Mutex
+	counters   map[string]*prometheus.CounterVec
+	gauges     map[string]*prometheus.GaugeVec
+	histograms map[string]*prometheus.HistogramVec
+}
+
+// NewPrometheusSink creates a Prometheus-backed Sink.
+func NewPrometheusSink(options Options) (*PrometheusSink, error) {
+	registerer := options.PrometheusRegisterer
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	return &PrometheusSink{
+		options:    options,
+		registerer: registerer,
+		counters:   map[string]*prometheus.CounterVec{},
+		gauges:     map[string]*prometheus.GaugeVec{},
+		histograms: map[string]*prometheus.HistogramVec{},
+	}, nil
+}
+
+// tagLabels splits Dogstatsd "key:value" tags into sorted label names and a
+// prometheus.Labels map. Tags without a colon are kept as boolean labels set
+// to "true".
+func tagLabels(tags []string) ([]string, prometheus.Labels) {
+	labels := make(prometheus.Labels, len(tags))
+	for _, tag := range tags {
+		key, value, found := strings.Cut(tag, ":")
+		if !found {
+			labels[key] = "true"
+			continue
+		}
+		labels[key] = value
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, labels
+}
+
+func (p *PrometheusSink) counterVec(name string, labelNames []string) *prometheus.CounterVec {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	vec, found := p.counters[name]
+	if !found {
+		vec = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: p.options.Namespace,
+			Name:      name,
+			Help:      fmt.Sprintf("dogstatsdclient counter %s", name),
+		}, labelNames)
+		p.registerer.MustRegister(vec)
+		p.counters[name] = vec
+	}
+	return vec
+}
+
+func (p *PrometheusSink) gaugeVec(name string, labelNames []string) *prometheus.GaugeVec {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	vec, found := p.gauges[name]
+	if !found {
+		vec = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: p.options.Namespace,
+			Name:      name,
+			Help:      fmt.Sprintf("dogstatsdclient gauge %s", name),
+		}, labelNames)
+		p.registerer.MustRegister(vec)
+		p.gauges[name] = vec
+	}
+	return vec
+}
+
+func (p *PrometheusSink) histogramVec(name string, labelNames []string) *prometheus.HistogramVec {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	vec, found := p.histograms[name]
+	if !found {
+		vec = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: p.options.Namespace,
+			Name:      name,
+			Help:      fmt.Sprintf("dogstatsdclient histogram %s", name),
+			Buckets:   prometheus.DefBuckets,
+		}, labelNames)
+		p.registerer.MustRegister(vec)
+		p.histograms[name] = vec
+	}
+	return vec
+}
+
+// Count tracks how many times something happened per second.
+func (p *PrometheusSink) Count(name string, value int64, tags []string, _ float64) error {
+	names, labels := tagLabels(tags)
+	p.counterVec(name, names).With(labels).Add(float64(value))
+	return nil
+}
+
+// Gauge measures the value of a metric at a particular time.
+func (p *PrometheusSink) Gauge(name string, value float64, tags []string, _ float64) error {
+	names, labels := tagLabels(tags)
+	p.gaugeVec(name, names).With(labels).Set(value)
+	return nil
+}
+
+// TimeInMilliseconds sends timing information in milliseconds.
+func (p *PrometheusSink) TimeInMilliseconds(name string, value float64, tags []string, _ float64) error {
+	names, labels := tagLabels(tags)
+	p.histogramVec(name, names).With(labels).Observe(value)
+	return nil
+}
+
+// Histogram tracks the statistical distribution of a set of values on each host.
+func (p *PrometheusSink) Histogram(name string, value float64, tags []string, _ float64) error {
+	names, labels := tagLabels(tags)
+	p.histogramVec(name, names).With(labels).Observe(value)
+	return nil
+}
+
+// Distribution tracks the statistical distribution of a set of values across your infrastructure.
+func (p *PrometheusSink) Distribution(name string, value float64, tags []string, _ float64) error {
+	return p.Histogram(name, value, tags, 1)
+}
+
+// Set counts the number of unique elements in a group.
+//
+// Prometheus has no native set-cardinality primitive, so this is approximated
+// by a counter of observations: true unique-element tracking is left to
+// whatever system scrapes these metrics.
+func (p *PrometheusSink) Set(name string, _ string, tags []string, _ float64) error {
+	names, labels := tagLabels(tags)
+	p.counterVec(name, names).With(labels).Inc()
+	return nil
+}
+
+// Incr is just Count of 1.
+func (p *PrometheusSink) Incr(name string, tags []string, rate float64) error {
+	return p.Count(name, 1, tags, rate)
+}
+
+// Decr is just Count of -1.
+func (p *PrometheusSink) Decr(name string, tags []string, rate float64) error {
+	return p.Count(name, -1, tags, rate)
+}
+
+// Timing sends timing information, it is an alias for TimeInMilliseconds.
+func (p *PrometheusSink) Timing(name string, value time.Duration, tags []string, rate float64) error {
+	return p.TimeInMilliseconds(name, float64(value)/float64(time.Millisecond), tags, rate)
+}
+
+// ServiceCheck has no Prometheus equivalent, it is logged and dropped.
+func (p *PrometheusSink) ServiceCheck(sc *statsd.ServiceCheck) error {
+	if p.options.Debug {
+		slog.Info("dogstatsdclient.PrometheusSink.ServiceCheck", "name", sc.Name, "status", sc.Status)
+	}
+	return nil
+}
+
+// Event has no Prometheus equivalent, it is logged and dropped.
+func (p *PrometheusSink) Event(e *statsd.Event) error {
+	if p.options.Debug {
+		slog.Info("dogstatsdclient.PrometheusSink.Event", "title", e.Title)
+	}
+	return nil
+}
+
+// Flush is a no-op: prometheus.Registerer metrics are pulled by a scraper, not pushed.
+func (p *PrometheusSink) Flush() error {
+	return nil
+}
+
+// Close is a no-op: PrometheusSink does not own any connection.
+func (p *PrometheusSink) Close() error {
+	return nil
+}
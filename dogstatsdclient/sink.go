@@ -0,0 +1,35 @@
+package dogstatsdclient
+
+import "fmt"
+
+// Sink is the common interface implemented by every metrics backend this
+// module can send to: the Dogstatsd-backed Client, PrometheusSink, OTLPSink
+// and MockSink.
+type Sink = DogstatsdClient
+
+// Backend names accepted in Options.Backend.
+const (
+	BackendDogstatsd  = "dogstatsd"
+	BackendPrometheus = "prometheus"
+	BackendOTLP       = "otlp"
+	BackendMock       = "mock"
+)
+
+// NewSink creates a Sink for the backend selected by options.Backend.
+// options.Backend defaults to BackendDogstatsd.
+func NewSink(options Options) (Sink, error) {
+	const me = "dogstatsdclient.NewSink"
+
+	switch options.Backend {
+	case "", BackendDogstatsd:
+		return New(options)
+	case BackendPrometheus:
+		return NewPrometheusSink(options)
+	case BackendOTLP:
+		return NewOTLPSink(options)
+	case BackendMock:
+		return NewMockSink(options), nil
+	default:
+		return nil, fmt.Errorf("%s: unknown backend: %s", me, options.Backend)
+	}
+}
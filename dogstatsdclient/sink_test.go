@@ -0,0 +1,71 @@
+package dogstatsdclient
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestNewSinkMock(t *testing.T) {
+	sink, err := NewSink(Options{Backend: BackendMock})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := sink.(*MockSink); !ok {
+		t.Fatalf("expected *MockSink, got %T", sink)
+	}
+}
+
+func TestNewSinkPrometheus(t *testing.T) {
+	sink, err := NewSink(Options{
+		Backend:              BackendPrometheus,
+		PrometheusRegisterer: prometheus.NewRegistry(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := sink.(*PrometheusSink); !ok {
+		t.Fatalf("expected *PrometheusSink, got %T", sink)
+	}
+	if err := sink.Count("requests", 1, []string{"route:/health"}, 1); err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+}
+
+func TestNewSinkOTLPRequiresMeter(t *testing.T) {
+	if _, err := NewSink(Options{Backend: BackendOTLP}); err == nil {
+		t.Fatal("expected error when OTelMeter is unset")
+	}
+}
+
+func TestNewSinkUnknownBackend(t *testing.T) {
+	if _, err := NewSink(Options{Backend: "bogus"}); err == nil {
+		t.Fatal("expected error for unknown backend")
+	}
+}
+
+func TestTagLabels(t *testing.T) {
+	names, labels := tagLabels([]string{"route:/health", "cached"})
+	if len(names) != 2 {
+		t.Fatalf("expected 2 label names, got %d: %v", len(names), names)
+	}
+	if labels["route"] != "/health" {
+		t.Fatalf("expected route label /health, got %q", labels["route"])
+	}
+	if labels["cached"] != "true" {
+		t.Fatalf("expected bare tag to become \"true\", got %q", labels["cached"])
+	}
+}
+
+func TestTagAttributes(t *testing.T) {
+	attrs := tagAttributes([]string{"route:/health", "cached"})
+	if len(attrs) != 2 {
+		t.Fatalf("expected 2 attributes, got %d: %v", len(attrs), attrs)
+	}
+	if attrs[0].Key != "route" || attrs[0].Value.AsString() != "/health" {
+		t.Fatalf("unexpected first attribute: %+v", attrs[0])
+	}
+	if attrs[1].Key != "cached" || !attrs[1].Value.AsBool() {
+		t.Fatalf("unexpected second attribute: %+v", attrs[1])
+	}
+}
@@ -3,7 +3,6 @@ package main
 
 import (
 	"flag"
-	"fmt"
 	"os"
 	"strings"
 	"time"
@@ -15,58 +14,77 @@ import (
 
 func main() {
 
-	var mock bool
+	var backend string
 	var unsafe bool
 	var sampleRate float64
+	var sampledRatePerSec float64
 	var namespace string
 	var tags string
 	var debug bool
 	var ttl time.Duration
+	var originDetection bool
+	var entityID string
 
-	flag.BoolVar(&debug, "debug", true, "enable debug")
-	flag.BoolVar(&mock, "mock", false, "enable mock")
-	flag.BoolVar(&unsafe, "unsafe", false, "create client UNSAFE for DNS changes")
-	flag.Float64Var(&sampleRate, "sampleRate", 1, "sample rate")
+	flag.StringVar(&backend, "backend", dogstatsdclient.BackendMock, "metrics backend: dogstatsd, prometheus, otlp or mock")
+	flag.BoolVar(&unsafe, "unsafe", false, "create client UNSAFE for DNS changes (only applies to the dogstatsd backend)")
+	flag.Float64Var(&sampleRate, "sampleRate", 1, "sample rate for the plain Count call")
+	flag.Float64Var(&sampledRatePerSec, "sampledRatePerSec", 2, "target rate per second for the CountSampled call")
 	flag.StringVar(&namespace, "namespace", "namespace1", "namespace")
 	flag.StringVar(&tags, "tags", "k1:v1 k2:v2", "space-delimited tags")
 	flag.DurationVar(&ttl, "ttl", 10*time.Second, "lifetime for safe client")
+	flag.BoolVar(&originDetection, "originDetection", false, "enable origin detection")
+	flag.StringVar(&entityID, "entityID", "", "entity ID tag, defaults to env var DD_ENTITY_ID")
+	flag.BoolVar(&debug, "debug", true, "enable debug")
 
 	flag.Parse()
 
 	slog.Info("flag",
-		"mock", mock,
+		"backend", backend,
 		"sampleRate", sampleRate,
+		"sampledRatePerSec", sampledRatePerSec,
 		"namespace", namespace,
 		"tags", tags,
 		"unsafe", unsafe,
 		"debug", debug,
 		"ttl", ttl,
+		"originDetection", originDetection,
+		"entityID", entityID,
 	)
 
 	//
 	// metrics exporter
 	//
 
+	onError := func(err error, metric string) {
+		slog.Error("dogstatsd error", "metric", metric, "error", err)
+	}
+
+	options := dogstatsdclient.Options{
+		Backend:         backend,
+		Namespace:       namespace,
+		Debug:           debug,
+		TTL:             ttl,
+		OriginDetection: originDetection,
+		EntityID:        entityID,
+		OnError:         onError,
+	}
+
 	var client dogstatsdclient.DogstatsdClient
 
-	if mock {
-		client = &statsdMock{}
-	} else {
-		options := dogstatsdclient.Options{
-			Namespace: namespace,
-			Debug:     debug,
-			TTL:       ttl,
-		}
-		var errClient error
-		if unsafe {
-			client, errClient = dogstatsdclient.NewUnsafe(options)
-		} else {
-			client, errClient = dogstatsdclient.New(options)
+	if unsafe && backend == dogstatsdclient.BackendDogstatsd {
+		c, errClient := dogstatsdclient.NewUnsafe(options)
+		if errClient != nil {
+			slog.Error(errClient.Error())
+			os.Exit(1)
 		}
+		client = c
+	} else {
+		c, errClient := dogstatsdclient.NewSink(options)
 		if errClient != nil {
 			slog.Error(errClient.Error())
 			os.Exit(1)
 		}
+		client = c
 	}
 
 	//
@@ -78,56 +96,28 @@ func main() {
 	t := strings.Fields(tags)
 
 	for {
-		send(client, "metric1", 3, t, sampleRate)
+		send(client, "metric1", 3, t, sampleRate, sampledRatePerSec)
+		reportClientStats(client)
 		time.Sleep(interval)
 	}
 }
 
-func send(client dogstatsdclient.DogstatsdClient, metric string, value int64, tags []string, sampleRate float64) {
-	slog.Info(fmt.Sprintf("sending COUNT name=%s value=%d", metric, value))
+func send(client dogstatsdclient.DogstatsdClient, metric string, value int64, tags []string, sampleRate, sampledRatePerSec float64) {
+	slog.Info("sending COUNT", "name", metric, "value", value, "rate", sampleRate)
 	client.Count(metric, value, tags, sampleRate)
-}
 
-type statsdMock struct {
-}
-
-// Gauge measures the value of a metric at a particular time.
-func (s *statsdMock) Gauge(name string, value float64, tags []string, rate float64) error {
-	slog.Info(
-		"statsdMock.Gauge",
-		"name", name,
-		"value", value,
-		"tags", tags,
-		"rate", rate,
-	)
-	return nil
-}
-
-// Count tracks how many times something happened per second.
-func (s *statsdMock) Count(name string, value int64, tags []string, rate float64) error {
-	slog.Info(
-		"statsdMock.Count",
-		"name", name,
-		"value", value,
-		"tags", tags,
-		"rate", rate,
-	)
-	return nil
-}
-
-// TimeInMilliseconds tracks how many times something happened per second.
-func (s *statsdMock) TimeInMilliseconds(name string, value float64, tags []string, rate float64) error {
-	slog.Info(
-		"statsdMock.TimeInMilliseconds",
-		"name", name,
-		"value", value,
-		"tags", tags,
-		"rate", rate,
-	)
-	return nil
+	if sampler, ok := client.(*dogstatsdclient.Client); ok {
+		slog.Info("sending CountSampled", "name", metric, "value", value, "targetRatePerSec", sampledRatePerSec)
+		sampler.CountSampled(metric, value, tags, sampledRatePerSec)
+	}
 }
 
-// Close the client connection.
-func (s *statsdMock) Close() error {
-	return nil
+// reportClientStats logs Client.Stats self-telemetry, when the selected
+// backend is the dogstatsd one (the only one that tracks drops/renewals).
+func reportClientStats(client dogstatsdclient.DogstatsdClient) {
+	c, ok := client.(*dogstatsdclient.Client)
+	if !ok {
+		return
+	}
+	slog.Info("client stats", "stats", c.Stats())
 }